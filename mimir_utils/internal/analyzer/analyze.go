@@ -0,0 +1,250 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/tsdb"
+	"github.com/prometheus/prometheus/v3/tsdb/chunks"
+	"github.com/prometheus/prometheus/v3/tsdb/index"
+)
+
+// LabelNameCount captures how many distinct values a label name takes on.
+type LabelNameCount struct {
+	Name  string
+	Count int
+}
+
+// LabelValueCount captures how many series a label value appears in, regardless of label name.
+type LabelValueCount struct {
+	Value  string
+	Series int
+}
+
+// LabelPairCount captures how many series a label name/value pair selects.
+type LabelPairCount struct {
+	Name   string
+	Value  string
+	Series int
+}
+
+// AnalysisReport summarizes label cardinality across a set of TSDB blocks.
+type AnalysisReport struct {
+	Series     int
+	Chunks     int
+	LabelPairs int
+
+	TopLabelNames  []LabelNameCount
+	TopLabelValues []LabelValueCount
+	TopPairs       []LabelPairCount
+	TopMetricNames []LabelPairCount
+}
+
+// Analyze walks the blocks under root (or, when blockULID is non-empty, just that block)
+// and reports label cardinality statistics without decoding any chunk bytes. When
+// includeHead is true, blocks are opened via OpenBlocks' read-only DB path, which returns
+// persisted blocks only; the head block is not analyzed.
+func Analyze(root, blockULID string, limit int, includeHead bool) (*AnalysisReport, error) {
+	blocks, closer, err := OpenBlocks(root, includeHead)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	if blockULID != "" {
+		blocks, err = selectBlock(blocks, blockULID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := &AnalysisReport{}
+	nameValues := map[string]map[string]struct{}{}
+	valueSeries := map[string]int{}
+	pairSeries := map[string]int{}
+	metricSeries := map[string]int{}
+
+	for _, block := range blocks {
+		if err := accumulateAnalysis(block, report, nameValues, valueSeries, pairSeries, metricSeries); err != nil {
+			return nil, fmt.Errorf("block %s: %w", block.Meta().ULID, err)
+		}
+	}
+
+	nameCardinality := make(map[string]int, len(nameValues))
+	for name, values := range nameValues {
+		nameCardinality[name] = len(values)
+	}
+
+	report.TopLabelNames = topLabelNames(nameCardinality, limit)
+	report.TopLabelValues = topLabelValues(valueSeries, limit)
+	report.TopPairs = topLabelPairs(pairSeries, limit)
+	report.TopMetricNames = topMetricNames(metricSeries, limit)
+
+	return report, nil
+}
+
+func selectBlock(blocks []tsdb.BlockReader, blockULID string) ([]tsdb.BlockReader, error) {
+	for _, block := range blocks {
+		if block.Meta().ULID.String() == blockULID {
+			return []tsdb.BlockReader{block}, nil
+		}
+	}
+	return nil, fmt.Errorf("block %s not found", blockULID)
+}
+
+func accumulateAnalysis(block tsdb.BlockReader, report *AnalysisReport, nameValues map[string]map[string]struct{}, valueSeries, pairSeries, metricSeries map[string]int) error {
+	indexReader, err := block.Index()
+	if err != nil {
+		return fmt.Errorf("open index: %w", err)
+	}
+	defer indexReader.Close()
+
+	allName, allValue := index.AllPostingsKey()
+	postings, err := indexReader.Postings(allName, allValue)
+	if err != nil {
+		return fmt.Errorf("load postings: %w", err)
+	}
+
+	for postings.Next() {
+		ref := postings.At()
+		var lset labels.Labels
+		var metas []chunks.Meta
+		if err := indexReader.Series(ref, &lset, &metas); err != nil {
+			return fmt.Errorf("read series %d: %w", ref, err)
+		}
+		report.Series++
+		report.Chunks += len(metas)
+	}
+	if err := postings.Err(); err != nil {
+		return fmt.Errorf("postings iteration: %w", err)
+	}
+
+	names, err := indexReader.LabelNames()
+	if err != nil {
+		return fmt.Errorf("label names: %w", err)
+	}
+
+	for _, labelName := range names {
+		values, err := indexReader.LabelValues(labelName)
+		if err != nil {
+			return fmt.Errorf("label values for %s: %w", labelName, err)
+		}
+
+		seen, ok := nameValues[labelName]
+		if !ok {
+			seen = make(map[string]struct{}, len(values))
+			nameValues[labelName] = seen
+		}
+		report.LabelPairs += len(values)
+
+		for _, labelValue := range values {
+			seen[labelValue] = struct{}{}
+			seriesCount, err := countPostings(indexReader, labelName, labelValue)
+			if err != nil {
+				return fmt.Errorf("postings for %s=%s: %w", labelName, labelValue, err)
+			}
+
+			valueSeries[labelValue] += seriesCount
+			pairSeries[labelName+"="+labelValue] += seriesCount
+			if labelName == "__name__" {
+				metricSeries[labelValue] += seriesCount
+			}
+		}
+	}
+
+	return nil
+}
+
+func countPostings(indexReader tsdb.IndexReader, name, value string) (int, error) {
+	postings, err := indexReader.Postings(name, value)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for postings.Next() {
+		count++
+	}
+	return count, postings.Err()
+}
+
+func topLabelNames(counts map[string]int, limit int) []LabelNameCount {
+	out := make([]LabelNameCount, 0, len(counts))
+	for name, count := range counts {
+		out = append(out, LabelNameCount{Name: name, Count: count})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count == out[j].Count {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Count > out[j].Count
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func topLabelValues(counts map[string]int, limit int) []LabelValueCount {
+	out := make([]LabelValueCount, 0, len(counts))
+	for value, series := range counts {
+		out = append(out, LabelValueCount{Value: value, Series: series})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Series == out[j].Series {
+			return out[i].Value < out[j].Value
+		}
+		return out[i].Series > out[j].Series
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func topLabelPairs(counts map[string]int, limit int) []LabelPairCount {
+	out := make([]LabelPairCount, 0, len(counts))
+	for key, series := range counts {
+		name, value := splitPairKey(key)
+		out = append(out, LabelPairCount{Name: name, Value: value, Series: series})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Series == out[j].Series {
+			return out[i].Name+out[i].Value < out[j].Name+out[j].Value
+		}
+		return out[i].Series > out[j].Series
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func topMetricNames(counts map[string]int, limit int) []LabelPairCount {
+	out := make([]LabelPairCount, 0, len(counts))
+	for value, series := range counts {
+		out = append(out, LabelPairCount{Name: "__name__", Value: value, Series: series})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Series == out[j].Series {
+			return out[i].Value < out[j].Value
+		}
+		return out[i].Series > out[j].Series
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// splitPairKey splits a "name=value" key built by accumulateAnalysis back into its parts.
+// Label names never contain '=', so the first occurrence is always the separator.
+func splitPairKey(key string) (string, string) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == '=' {
+			return key[:i], key[i+1:]
+		}
+	}
+	return key, ""
+}
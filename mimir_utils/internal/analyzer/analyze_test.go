@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/storage"
+	"github.com/prometheus/prometheus/v3/tsdb"
+	"github.com/prometheus/prometheus/v3/tsdb/tsdbutil"
+)
+
+// TestAnalyzeLabelNameCardinalityIsDistinctAcrossBlocks makes sure a label whose values
+// repeat in every block (e.g. "namespace" in a Mimir tenant) is reported with its true
+// distinct cardinality, not the sum of per-block distinct counts.
+func TestAnalyzeLabelNameCardinalityIsDistinctAcrossBlocks(t *testing.T) {
+	const (
+		numBlocks          = 3
+		namespacesPerBlock = 10
+	)
+
+	dir := t.TempDir()
+
+	for i := 0; i < numBlocks; i++ {
+		mint := int64(i) * 2 * time.Hour.Milliseconds()
+		maxt := mint + 2*time.Hour.Milliseconds()
+
+		var series []storage.Series
+		for j := 0; j < namespacesPerBlock; j++ {
+			lset := labels.FromStrings(
+				"__name__", "up",
+				"namespace", fmt.Sprintf("ns-%d", j),
+			)
+			series = append(series, storage.NewListSeries(lset, tsdbutil.GenerateSamples(int(mint), 1)))
+		}
+
+		if _, err := tsdb.CreateBlock(series, dir, mint, maxt, nil); err != nil {
+			t.Fatalf("create block %d: %v", i, err)
+		}
+	}
+
+	report, err := Analyze(dir, "", 0, false)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	var got *LabelNameCount
+	for i := range report.TopLabelNames {
+		if report.TopLabelNames[i].Name == "namespace" {
+			got = &report.TopLabelNames[i]
+			break
+		}
+	}
+	if got == nil {
+		t.Fatalf("namespace label not found in %+v", report.TopLabelNames)
+	}
+	if got.Count != namespacesPerBlock {
+		t.Fatalf("namespace cardinality = %d, want %d (distinct across %d blocks sharing the same values)", got.Count, namespacesPerBlock, numBlocks)
+	}
+}
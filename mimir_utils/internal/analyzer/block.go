@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/tsdb"
+	"github.com/prometheus/prometheus/v3/tsdb/index"
+)
+
+// OpenBlocks discovers the TSDB blocks under root and returns a BlockReader for each,
+// along with a closer that must be called once the caller is done with them.
+//
+// When includeHead is false, only persisted blocks (directories containing meta.json)
+// are opened, matching what top-metrics has always scanned. When includeHead is true,
+// root is opened as a live TSDB data directory via tsdb.OpenDBReadOnly and the blocks
+// returned are those of db.Blocks(), which lists persisted blocks only: the head block
+// built from wal/ and chunks_head/ is not returned, so unflushed samples are not visible.
+func OpenBlocks(root string, includeHead bool) ([]tsdb.BlockReader, io.Closer, error) {
+	if includeHead {
+		return openReadOnlyDBBlocks(root)
+	}
+	return openPersistedBlocks(root)
+}
+
+func openPersistedBlocks(root string) ([]tsdb.BlockReader, io.Closer, error) {
+	blockDirs, err := findBlockDirs(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var blocks []tsdb.BlockReader
+	var closers multiCloser
+	for _, blockDir := range blockDirs {
+		block, err := tsdb.OpenBlock(nil, blockDir, nil, nil)
+		if err != nil {
+			closers.Close()
+			return nil, nil, fmt.Errorf("open block %s: %w", blockDir, err)
+		}
+		blocks = append(blocks, block)
+		closers = append(closers, block)
+	}
+
+	return blocks, closers, nil
+}
+
+func openReadOnlyDBBlocks(root string) ([]tsdb.BlockReader, io.Closer, error) {
+	db, err := tsdb.OpenDBReadOnly(root, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open read-only db: %w", err)
+	}
+
+	blocks, err := db.Blocks()
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("list blocks: %w", err)
+	}
+
+	return blocks, db, nil
+}
+
+// multiCloser closes every io.Closer it holds, continuing past the first error so that
+// a failure partway through opening blocks doesn't leak the ones already opened.
+type multiCloser []io.Closer
+
+func (m multiCloser) Close() error {
+	var firstErr error
+	for _, c := range m {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func findBlockDirs(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dirPath := filepath.Join(root, entry.Name())
+		if _, err := os.Stat(filepath.Join(dirPath, "meta.json")); err == nil {
+			blocks = append(blocks, dirPath)
+		}
+	}
+
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no TSDB blocks found in %s", root)
+	}
+
+	return blocks, nil
+}
+
+// matchingPostings resolves the postings selected by matchers, or every series in the
+// block when matchers is empty.
+func matchingPostings(indexReader tsdb.IndexReader, matchers []*labels.Matcher) (index.Postings, error) {
+	if len(matchers) == 0 {
+		name, value := index.AllPostingsKey()
+		return indexReader.Postings(name, value)
+	}
+	return tsdb.PostingsForMatchers(indexReader, matchers...)
+}
+
+// mergedPostings resolves the union of the postings selected by each set of matchers in
+// matcherSets, so that a series matching any one selector is visited exactly once. An
+// empty matcherSets selects every series in the block.
+func mergedPostings(indexReader tsdb.IndexReader, matcherSets [][]*labels.Matcher) (index.Postings, error) {
+	if len(matcherSets) == 0 {
+		matcherSets = [][]*labels.Matcher{nil}
+	}
+
+	sets := make([]index.Postings, 0, len(matcherSets))
+	for _, matchers := range matcherSets {
+		postings, err := matchingPostings(indexReader, matchers)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, postings)
+	}
+
+	if len(sets) == 1 {
+		return sets[0], nil
+	}
+	return index.Merge(context.Background(), sets...), nil
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/prometheus/v3/model/histogram"
+	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/tsdb"
+	"github.com/prometheus/prometheus/v3/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/v3/tsdb/chunks"
+)
+
+// SampleType selects which chunk encodings DumpSamples emits.
+type SampleType string
+
+// Recognized SampleType values for DumpSamples.
+const (
+	SampleTypeFloat     SampleType = "float"
+	SampleTypeHistogram SampleType = "histogram"
+	SampleTypeAll       SampleType = "all"
+)
+
+// DumpSamples writes every sample selected by matcherSets (series matching any one set
+// are included, mirroring repeated -match flags) and within [minTime, maxTime] to w, one
+// "{labels} value timestamp_ms" line per sample.
+func DumpSamples(root string, matcherSets [][]*labels.Matcher, minTime, maxTime int64, sampleType SampleType, includeHead bool, w *bufio.Writer) error {
+	blocks, closer, err := OpenBlocks(root, includeHead)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	for _, block := range blocks {
+		if err := dumpBlock(block, matcherSets, minTime, maxTime, sampleType, w); err != nil {
+			return fmt.Errorf("block %s: %w", block.Meta().ULID, err)
+		}
+	}
+
+	return nil
+}
+
+func dumpBlock(block tsdb.BlockReader, matcherSets [][]*labels.Matcher, minTime, maxTime int64, sampleType SampleType, w *bufio.Writer) error {
+	indexReader, err := block.Index()
+	if err != nil {
+		return fmt.Errorf("open index: %w", err)
+	}
+	defer indexReader.Close()
+
+	chunkReader, err := block.Chunks()
+	if err != nil {
+		return fmt.Errorf("open chunks: %w", err)
+	}
+	defer chunkReader.Close()
+
+	postings, err := mergedPostings(indexReader, matcherSets)
+	if err != nil {
+		return fmt.Errorf("load postings: %w", err)
+	}
+
+	for postings.Next() {
+		ref := postings.At()
+		var lset labels.Labels
+		var metas []chunks.Meta
+		if err := indexReader.Series(ref, &lset, &metas); err != nil {
+			return fmt.Errorf("read series %d: %w", ref, err)
+		}
+
+		for _, meta := range metas {
+			if meta.MaxTime < minTime || meta.MinTime > maxTime {
+				continue
+			}
+
+			chk, err := chunkReader.Chunk(meta)
+			if err != nil {
+				return fmt.Errorf("read chunk %d: %w", meta.Ref, err)
+			}
+
+			if err := dumpChunk(w, lset, chk, minTime, maxTime, sampleType); err != nil {
+				return fmt.Errorf("dump chunk %d: %w", meta.Ref, err)
+			}
+		}
+	}
+
+	return postings.Err()
+}
+
+// dumpChunk writes the samples of a single chunk that fall in [minTime, maxTime] and
+// match sampleType.
+func dumpChunk(w *bufio.Writer, lset labels.Labels, chk chunkenc.Chunk, minTime, maxTime int64, sampleType SampleType) error {
+	it := chk.Iterator(nil)
+	for valType := it.Next(); valType != chunkenc.ValNone; valType = it.Next() {
+		var t int64
+		var value string
+
+		switch valType {
+		case chunkenc.ValFloat:
+			if sampleType == SampleTypeHistogram {
+				continue
+			}
+			var v float64
+			t, v = it.At()
+			value = strconv.FormatFloat(v, 'g', -1, 64)
+		case chunkenc.ValHistogram:
+			if sampleType == SampleTypeFloat {
+				continue
+			}
+			var h *histogram.Histogram
+			t, h = it.AtHistogram(nil)
+			value = fmt.Sprintf("count:%d sum:%g", h.Count, h.Sum)
+		case chunkenc.ValFloatHistogram:
+			if sampleType == SampleTypeFloat {
+				continue
+			}
+			var h *histogram.FloatHistogram
+			t, h = it.AtFloatHistogram(nil)
+			value = fmt.Sprintf("count:%g sum:%g", h.Count, h.Sum)
+		default:
+			continue
+		}
+
+		if t < minTime || t > maxTime {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s %s %d\n", lset.String(), value, t); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// blockMeta mirrors the subset of a TSDB block's meta.json that ls needs, so ls doesn't
+// have to depend on the full tsdb package just to read block metadata.
+type blockMeta struct {
+	ULID    string `json:"ulid"`
+	MinTime int64  `json:"minTime"`
+	MaxTime int64  `json:"maxTime"`
+	Stats   struct {
+		NumSamples uint64 `json:"numSamples"`
+		NumSeries  uint64 `json:"numSeries"`
+		NumChunks  uint64 `json:"numChunks"`
+	} `json:"stats"`
+	Compaction struct {
+		Level   int      `json:"level"`
+		Sources []string `json:"sources"`
+		Parents []struct {
+			ULID string `json:"ulid"`
+		} `json:"parents"`
+	} `json:"compaction"`
+}
+
+// BlockSummary is one row of `ls` output: a block's meta.json fields plus its on-disk size.
+type BlockSummary struct {
+	ULID            string   `json:"ulid"`
+	MinTime         int64    `json:"minTime"`
+	MaxTime         int64    `json:"maxTime"`
+	Series          uint64   `json:"series"`
+	Chunks          uint64   `json:"chunks"`
+	Samples         uint64   `json:"samples"`
+	Bytes           int64    `json:"bytes"`
+	CompactionLevel int      `json:"compactionLevel"`
+	Sources         int      `json:"sources"`
+	Parents         []string `json:"parents"`
+}
+
+// ListBlocks scans root for TSDB block directories and returns one BlockSummary per
+// persisted block, sorted by MinTime.
+func ListBlocks(root string) ([]BlockSummary, error) {
+	blockDirs, err := findBlockDirs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]BlockSummary, 0, len(blockDirs))
+	for _, blockDir := range blockDirs {
+		summary, err := summarizeBlock(blockDir)
+		if err != nil {
+			return nil, fmt.Errorf("block %s: %w", blockDir, err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].MinTime < summaries[j].MinTime
+	})
+
+	return summaries, nil
+}
+
+func summarizeBlock(blockDir string) (BlockSummary, error) {
+	metaBytes, err := os.ReadFile(filepath.Join(blockDir, "meta.json"))
+	if err != nil {
+		return BlockSummary{}, fmt.Errorf("read meta.json: %w", err)
+	}
+
+	var meta blockMeta
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		return BlockSummary{}, fmt.Errorf("parse meta.json: %w", err)
+	}
+
+	size, err := dirSize(blockDir)
+	if err != nil {
+		return BlockSummary{}, fmt.Errorf("compute size: %w", err)
+	}
+
+	parents := make([]string, 0, len(meta.Compaction.Parents))
+	for _, p := range meta.Compaction.Parents {
+		parents = append(parents, p.ULID)
+	}
+
+	return BlockSummary{
+		ULID:            meta.ULID,
+		MinTime:         meta.MinTime,
+		MaxTime:         meta.MaxTime,
+		Series:          meta.Stats.NumSeries,
+		Chunks:          meta.Stats.NumChunks,
+		Samples:         meta.Stats.NumSamples,
+		Bytes:           size,
+		CompactionLevel: meta.Compaction.Level,
+		Sources:         len(meta.Compaction.Sources),
+		Parents:         parents,
+	}, nil
+}
+
+// dirSize sums the size of index, chunks/*, and tombstones under blockDir, the files that
+// make up a block's on-disk footprint.
+func dirSize(blockDir string) (int64, error) {
+	var total int64
+
+	for _, name := range []string{"index", "tombstones"} {
+		info, err := os.Stat(filepath.Join(blockDir, name))
+		if err == nil {
+			total += info.Size()
+		} else if !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+
+	chunkEntries, err := os.ReadDir(filepath.Join(blockDir, "chunks"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return total, nil
+		}
+		return 0, err
+	}
+	for _, entry := range chunkEntries {
+		info, err := entry.Info()
+		if err != nil {
+			return 0, err
+		}
+		total += info.Size()
+	}
+
+	return total, nil
+}
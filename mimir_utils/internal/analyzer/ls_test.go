@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/storage"
+	"github.com/prometheus/prometheus/v3/tsdb"
+	"github.com/prometheus/prometheus/v3/tsdb/tsdbutil"
+)
+
+// TestListBlocksSortedByMinTime confirms ListBlocks reads meta.json fields for each block
+// and returns them ordered oldest-first regardless of directory creation order.
+func TestListBlocksSortedByMinTime(t *testing.T) {
+	dir := t.TempDir()
+
+	mints := []int64{2 * time.Hour.Milliseconds(), 0}
+	for i, mint := range mints {
+		maxt := mint + time.Hour.Milliseconds()
+		lset := labels.FromStrings("__name__", fmt.Sprintf("metric_%d", i))
+		series := []storage.Series{
+			storage.NewListSeries(lset, tsdbutil.GenerateSamples(int(mint), 5)),
+		}
+		if _, err := tsdb.CreateBlock(series, dir, mint, maxt, nil); err != nil {
+			t.Fatalf("create block %d: %v", i, err)
+		}
+	}
+
+	summaries, err := ListBlocks(dir)
+	if err != nil {
+		t.Fatalf("ListBlocks: %v", err)
+	}
+
+	if len(summaries) != 2 {
+		t.Fatalf("got %d summaries, want 2", len(summaries))
+	}
+	if summaries[0].MinTime != 0 || summaries[1].MinTime != 2*time.Hour.Milliseconds() {
+		t.Fatalf("summaries not sorted by MinTime: %+v", summaries)
+	}
+	for _, s := range summaries {
+		if s.Bytes <= 0 {
+			t.Fatalf("block %s has non-positive on-disk size %d", s.ULID, s.Bytes)
+		}
+		if s.Series == 0 {
+			t.Fatalf("block %s has zero series, want at least one", s.ULID)
+		}
+	}
+}
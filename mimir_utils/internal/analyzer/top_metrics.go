@@ -1,38 +1,109 @@
 package analyzer
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/tsdb"
 	"github.com/prometheus/prometheus/v3/tsdb/chunkenc"
 	"github.com/prometheus/prometheus/v3/tsdb/chunks"
-	"github.com/prometheus/prometheus/v3/tsdb/index"
 )
 
 // MetricStat captures byte usage information for a metric across a set of blocks.
 type MetricStat struct {
-	Name   string
-	Bytes  int64
-	Series int
-	Chunks int
+	Name    string
+	Bytes   int64
+	Series  int
+	Chunks  int
+	Samples int
 }
 
-// TopNMetrics walks the provided directory for TSDB blocks and returns the top metrics by bytes used.
-func TopNMetrics(root string, limit int) ([]MetricStat, error) {
-	aggregate := map[string]*MetricStat{}
+// TopMetricsOptions configures a TopNMetrics scan.
+type TopMetricsOptions struct {
+	// Limit caps the number of metrics returned (0 for all).
+	Limit int
+	// IncludeHead opens root via tsdb.OpenDBReadOnly instead of scanning block directories.
+	// Only persisted blocks are scanned either way; the head block is not included.
+	IncludeHead bool
+	// Concurrency caps how many blocks are scanned at once (0 for runtime.GOMAXPROCS).
+	Concurrency int
+	// MatcherSets restricts the scan to series matching any one of these PromQL selectors.
+	// An empty MatcherSets scans every series.
+	MatcherSets [][]*labels.Matcher
+	// MinTime and MaxTime restrict the scan to chunks overlapping [MinTime, MaxTime].
+	// Callers that want an unbounded side must pass math.MinInt64/math.MaxInt64
+	// explicitly; 0 is a legitimate timestamp and is not treated as a sentinel here.
+	MinTime, MaxTime int64
+	// CountSamples decodes each chunk's iterator to populate MetricStat.Samples. This is
+	// more expensive than just summing chunk bytes, so it's opt-in.
+	CountSamples bool
+}
 
-	blockDirs, err := findBlockDirs(root)
+// TopNMetrics walks the provided directory for TSDB blocks and returns the top metrics by
+// bytes used. Blocks are scanned concurrently across up to opts.Concurrency workers; a
+// corrupt block aborts the run once its error is observed.
+func TopNMetrics(root string, opts TopMetricsOptions) ([]MetricStat, error) {
+	blocks, closer, err := OpenBlocks(root, opts.IncludeHead)
 	if err != nil {
 		return nil, err
 	}
+	defer closer.Close()
+
+	minTime, maxTime := opts.MinTime, opts.MaxTime
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	perBlock := make([]map[string]MetricStat, len(blocks))
 
-	for _, blockDir := range blockDirs {
-		if err := accumulateBlock(blockDir, aggregate); err != nil {
-			return nil, fmt.Errorf("block %s: %w", blockDir, err)
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(concurrency)
+
+	for i, block := range blocks {
+		i, block := i, block
+
+		meta := block.Meta()
+		if meta.MaxTime < minTime || meta.MinTime > maxTime {
+			continue
+		}
+
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			stats, err := accumulateBlock(block, opts.MatcherSets, minTime, maxTime, opts.CountSamples)
+			if err != nil {
+				return fmt.Errorf("block %s: %w", meta.ULID, err)
+			}
+			perBlock[i] = stats
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	aggregate := map[string]*MetricStat{}
+	for _, blockStats := range perBlock {
+		for name, stat := range blockStats {
+			agg, ok := aggregate[name]
+			if !ok {
+				agg = &MetricStat{Name: name}
+				aggregate[name] = agg
+			}
+			agg.Bytes += stat.Bytes
+			agg.Series += stat.Series
+			agg.Chunks += stat.Chunks
+			agg.Samples += stat.Samples
 		}
 	}
 
@@ -48,67 +119,43 @@ func TopNMetrics(root string, limit int) ([]MetricStat, error) {
 		return stats[i].Bytes > stats[j].Bytes
 	})
 
-	if limit > 0 && len(stats) > limit {
-		stats = stats[:limit]
+	if opts.Limit > 0 && len(stats) > opts.Limit {
+		stats = stats[:opts.Limit]
 	}
 
 	return stats, nil
 }
 
-func findBlockDirs(root string) ([]string, error) {
-	entries, err := os.ReadDir(root)
+// accumulateBlock returns the byte usage of every metric in block, keyed by metric name,
+// restricted to series matching matcherSets and chunks overlapping [minTime, maxTime]. It
+// is safe to call concurrently for different blocks since it never touches shared state.
+func accumulateBlock(block tsdb.BlockReader, matcherSets [][]*labels.Matcher, minTime, maxTime int64, countSamples bool) (map[string]MetricStat, error) {
+	indexReader, err := block.Index()
 	if err != nil {
-		return nil, err
-	}
-
-	var blocks []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		dirPath := filepath.Join(root, entry.Name())
-		if _, err := os.Stat(filepath.Join(dirPath, "meta.json")); err == nil {
-			blocks = append(blocks, dirPath)
-		}
-	}
-
-	if len(blocks) == 0 {
-		return nil, fmt.Errorf("no TSDB blocks found in %s", root)
-	}
-
-	return blocks, nil
-}
-
-func accumulateBlock(blockDir string, aggregate map[string]*MetricStat) error {
-	indexPath := filepath.Join(blockDir, "index")
-	chunkDir := filepath.Join(blockDir, "chunks")
-
-	indexReader, err := index.NewFileReader(indexPath)
-	if err != nil {
-		return fmt.Errorf("open index: %w", err)
+		return nil, fmt.Errorf("open index: %w", err)
 	}
 	defer indexReader.Close()
 
-	pool := chunkenc.NewPool()
-	chunkReader, err := chunks.NewDirReader(chunkDir, pool)
+	chunkReader, err := block.Chunks()
 	if err != nil {
-		return fmt.Errorf("open chunks: %w", err)
+		return nil, fmt.Errorf("open chunks: %w", err)
 	}
 	defer chunkReader.Close()
 
-	name, value := index.AllPostingsKey()
-	postings, err := indexReader.Postings(name, value)
+	postings, err := mergedPostings(indexReader, matcherSets)
 	if err != nil {
-		return fmt.Errorf("load postings: %w", err)
+		return nil, fmt.Errorf("load postings: %w", err)
 	}
 
+	aggregate := map[string]MetricStat{}
+
 	for postings.Next() {
 		ref := postings.At()
 		var lset labels.Labels
 		var metas []chunks.Meta
 
 		if err := indexReader.Series(ref, &lset, &metas); err != nil {
-			return fmt.Errorf("read series %d: %w", ref, err)
+			return nil, fmt.Errorf("read series %d: %w", ref, err)
 		}
 
 		metricName := lset.Get("__name__")
@@ -117,30 +164,63 @@ func accumulateBlock(blockDir string, aggregate map[string]*MetricStat) error {
 		}
 
 		var seriesBytes int64
+		var seriesChunks, seriesSamples int
 		for _, meta := range metas {
-			chk, err := chunkReader.Chunk(meta.Ref)
+			if meta.MaxTime < minTime || meta.MinTime > maxTime {
+				continue
+			}
+
+			chk, err := chunkReader.Chunk(meta)
 			if err != nil {
 				if strings.Contains(err.Error(), "reference") {
-					return fmt.Errorf("chunk %d: %w", meta.Ref, err)
+					return nil, fmt.Errorf("chunk %d: %w", meta.Ref, err)
 				}
-				return fmt.Errorf("read chunk %d: %w", meta.Ref, err)
+				return nil, fmt.Errorf("read chunk %d: %w", meta.Ref, err)
 			}
 			seriesBytes += int64(len(chk.Bytes()))
-		}
+			seriesChunks++
 
-		stat, ok := aggregate[metricName]
-		if !ok {
-			stat = &MetricStat{Name: metricName}
-			aggregate[metricName] = stat
+			if countSamples {
+				seriesSamples += countChunkSamples(chk, minTime, maxTime)
+			}
 		}
+
+		stat := aggregate[metricName]
+		stat.Name = metricName
 		stat.Bytes += seriesBytes
 		stat.Series++
-		stat.Chunks += len(metas)
+		stat.Chunks += seriesChunks
+		stat.Samples += seriesSamples
+		aggregate[metricName] = stat
 	}
 
 	if err := postings.Err(); err != nil {
-		return fmt.Errorf("postings iteration: %w", err)
+		return nil, fmt.Errorf("postings iteration: %w", err)
 	}
 
-	return nil
+	return aggregate, nil
+}
+
+// countChunkSamples decodes chk's iterator and counts the samples within [minTime, maxTime].
+func countChunkSamples(chk chunkenc.Chunk, minTime, maxTime int64) int {
+	count := 0
+	it := chk.Iterator(nil)
+	for valType := it.Next(); valType != chunkenc.ValNone; valType = it.Next() {
+		var t int64
+		switch valType {
+		case chunkenc.ValFloat:
+			t, _ = it.At()
+		case chunkenc.ValHistogram:
+			t, _ = it.AtHistogram(nil)
+		case chunkenc.ValFloatHistogram:
+			t, _ = it.AtFloatHistogram(nil)
+		default:
+			continue
+		}
+		if t < minTime || t > maxTime {
+			continue
+		}
+		count++
+	}
+	return count
 }
@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/storage"
+	"github.com/prometheus/prometheus/v3/tsdb"
+	"github.com/prometheus/prometheus/v3/tsdb/tsdbutil"
+)
+
+// BenchmarkTopNMetrics measures TopNMetrics throughput over a handful of synthetic blocks,
+// the shape of scan it's meant to speed up by parallelizing across blocks.
+func BenchmarkTopNMetrics(b *testing.B) {
+	const (
+		numBlocks        = 8
+		seriesPerBlock   = 2000
+		samplesPerSeries = 120
+	)
+
+	dir := b.TempDir()
+
+	for i := 0; i < numBlocks; i++ {
+		mint := int64(i) * 2 * time.Hour.Milliseconds()
+		maxt := mint + 2*time.Hour.Milliseconds()
+
+		series := make([]storage.Series, 0, seriesPerBlock)
+		for j := 0; j < seriesPerBlock; j++ {
+			lset := labels.FromStrings(
+				"__name__", fmt.Sprintf("metric_%d", j%50),
+				"block", fmt.Sprintf("%d", i),
+				"series", fmt.Sprintf("%d", j),
+			)
+			series = append(series, storage.NewListSeries(lset, tsdbutil.GenerateSamples(int(mint), samplesPerSeries)))
+		}
+
+		if _, err := tsdb.CreateBlock(series, dir, mint, maxt, nil); err != nil {
+			b.Fatalf("create block %d: %v", i, err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := TopNMetrics(dir, TopMetricsOptions{Limit: 10, MinTime: math.MinInt64, MaxTime: math.MaxInt64}); err != nil {
+			b.Fatalf("TopNMetrics: %v", err)
+		}
+	}
+}
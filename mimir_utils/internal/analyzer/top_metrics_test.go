@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/promql/parser"
+	"github.com/prometheus/prometheus/v3/storage"
+	"github.com/prometheus/prometheus/v3/tsdb"
+	"github.com/prometheus/prometheus/v3/tsdb/tsdbutil"
+)
+
+func buildTopMetricsTestBlocks(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	blocks := []struct {
+		namespace string
+		mint      int64
+	}{
+		{"foo", 0},
+		{"bar", 2 * time.Hour.Milliseconds()},
+	}
+
+	for i, b := range blocks {
+		mint := b.mint
+		maxt := mint + 2*time.Hour.Milliseconds()
+
+		lset := labels.FromStrings("__name__", "up", "namespace", b.namespace)
+		series := []storage.Series{
+			storage.NewListSeries(lset, tsdbutil.GenerateSamples(int(mint), 10)),
+		}
+
+		if _, err := tsdb.CreateBlock(series, dir, mint, maxt, nil); err != nil {
+			t.Fatalf("create block %d: %v", i, err)
+		}
+	}
+
+	return dir
+}
+
+// TestTopNMetricsMatcherSetsFilter confirms a -match selector restricts the scan to the
+// matching series instead of every series in the block.
+func TestTopNMetricsMatcherSetsFilter(t *testing.T) {
+	dir := buildTopMetricsTestBlocks(t)
+
+	matchers, err := parser.ParseMetricSelector(`up{namespace="foo"}`)
+	if err != nil {
+		t.Fatalf("parse matcher: %v", err)
+	}
+
+	stats, err := TopNMetrics(dir, TopMetricsOptions{
+		MatcherSets: [][]*labels.Matcher{matchers},
+		MinTime:     math.MinInt64,
+		MaxTime:     math.MaxInt64,
+	})
+	if err != nil {
+		t.Fatalf("TopNMetrics: %v", err)
+	}
+
+	if len(stats) != 1 {
+		t.Fatalf("stats = %+v, want exactly one metric matching namespace=foo", stats)
+	}
+	if stats[0].Series != 1 {
+		t.Fatalf("stats[0].Series = %d, want 1 (bar's series should be excluded by the matcher)", stats[0].Series)
+	}
+}
+
+// TestTopNMetricsTimeRangeExcludesNonOverlappingBlocks confirms a time window that only
+// covers the first block's range excludes series that only exist in the second block, and
+// that an explicit MinTime of 0 (a legitimate timestamp, not a sentinel) is honored.
+func TestTopNMetricsTimeRangeExcludesNonOverlappingBlocks(t *testing.T) {
+	dir := buildTopMetricsTestBlocks(t)
+
+	stats, err := TopNMetrics(dir, TopMetricsOptions{
+		MinTime: 0,
+		MaxTime: time.Hour.Milliseconds(),
+	})
+	if err != nil {
+		t.Fatalf("TopNMetrics: %v", err)
+	}
+
+	if len(stats) != 1 {
+		t.Fatalf("stats = %+v, want only the first block's series within [0, 1h]", stats)
+	}
+	if stats[0].Series != 1 {
+		t.Fatalf("stats[0].Series = %d, want 1 (bar's block at mint=2h should be excluded)", stats[0].Series)
+	}
+}
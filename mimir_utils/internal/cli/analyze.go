@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"mimir_utils/internal/analyzer"
+)
+
+func runAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	dir := fs.String("dir", "", "Directory containing TSDB blocks")
+	block := fs.String("block", "", "Only analyze the block with this ULID")
+	limit := fs.Int("limit", 10, "Number of rows to display per table (0 for all)")
+	includeHead := fs.Bool("include-head", false, "Open -dir via a read-only DB (persisted blocks only; the head block is not scanned)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), `Usage: mimir_utils analyze [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		fs.Usage()
+		return fmt.Errorf("the -dir flag is required")
+	}
+
+	report, err := analyzer.Analyze(*dir, *block, *limit, *includeHead)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Series: %d\nChunks: %d\nLabel pairs: %d\n\n", report.Series, report.Chunks, report.LabelPairs)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+
+	fmt.Fprintln(w, "LABEL NAME\tDISTINCT VALUES")
+	for _, row := range report.TopLabelNames {
+		fmt.Fprintf(w, "%s\t%d\n", row.Name, row.Count)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "LABEL VALUE\tSERIES")
+	for _, row := range report.TopLabelValues {
+		fmt.Fprintf(w, "%s\t%d\n", row.Value, row.Series)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "LABEL PAIR\tSERIES")
+	for _, row := range report.TopPairs {
+		fmt.Fprintf(w, "%s=%s\t%d\n", row.Name, row.Value, row.Series)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "METRIC NAME\tSERIES")
+	for _, row := range report.TopMetricNames {
+		fmt.Fprintf(w, "%s\t%d\n", row.Value, row.Series)
+	}
+
+	return w.Flush()
+}
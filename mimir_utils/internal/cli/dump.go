@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/v3/model/labels"
+	"github.com/prometheus/prometheus/v3/promql/parser"
+
+	"mimir_utils/internal/analyzer"
+)
+
+// matcherFlags collects repeated -match flag values, each parsed as a PromQL metric selector.
+type matcherFlags struct {
+	sets [][]*labels.Matcher
+}
+
+func (m *matcherFlags) String() string {
+	return ""
+}
+
+func (m *matcherFlags) Set(value string) error {
+	matchers, err := parser.ParseMetricSelector(value)
+	if err != nil {
+		return fmt.Errorf("parse -match %q: %w", value, err)
+	}
+	m.sets = append(m.sets, matchers)
+	return nil
+}
+
+func runDump(args []string) error {
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	dir := fs.String("dir", "", "Directory containing TSDB blocks")
+	minTimeStr := fs.String("min-time", "", "Only dump samples at or after this time (RFC3339 or unix ms)")
+	maxTimeStr := fs.String("max-time", "", "Only dump samples at or before this time (RFC3339 or unix ms)")
+	sampleType := fs.String("sample-type", "all", "Sample types to dump: float, histogram, or all")
+	includeHead := fs.Bool("include-head", false, "Open -dir via a read-only DB (persisted blocks only; the head block is not scanned)")
+
+	var matchers matcherFlags
+	fs.Var(&matchers, "match", "PromQL metric selector to filter series by (repeatable)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), `Usage: mimir_utils dump [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		fs.Usage()
+		return fmt.Errorf("the -dir flag is required")
+	}
+
+	st := analyzer.SampleType(*sampleType)
+	switch st {
+	case analyzer.SampleTypeFloat, analyzer.SampleTypeHistogram, analyzer.SampleTypeAll:
+	default:
+		return fmt.Errorf("invalid -sample-type %q: must be float, histogram, or all", *sampleType)
+	}
+
+	minTime := int64(math.MinInt64)
+	if *minTimeStr != "" {
+		t, err := parseTime(*minTimeStr)
+		if err != nil {
+			return fmt.Errorf("parse -min-time: %w", err)
+		}
+		minTime = t
+	}
+
+	maxTime := int64(math.MaxInt64)
+	if *maxTimeStr != "" {
+		t, err := parseTime(*maxTimeStr)
+		if err != nil {
+			return fmt.Errorf("parse -max-time: %w", err)
+		}
+		maxTime = t
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	if err := analyzer.DumpSamples(*dir, matchers.sets, minTime, maxTime, st, *includeHead, w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// parseTime accepts either an RFC3339 timestamp or a unix timestamp in milliseconds.
+func parseTime(s string) (int64, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ms, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("not a unix ms timestamp or RFC3339 time: %q", strings.TrimSpace(s))
+	}
+	return t.UnixMilli(), nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mimir_utils/internal/analyzer"
+)
+
+func runLs(args []string) error {
+	fs := flag.NewFlagSet("ls", flag.ContinueOnError)
+	dir := fs.String("dir", "", "Directory containing TSDB blocks")
+	jsonOutput := fs.Bool("json", false, "Print one JSON object per block instead of a table")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), `Usage: mimir_utils ls [options]
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		fs.Usage()
+		return fmt.Errorf("the -dir flag is required")
+	}
+
+	summaries, err := analyzer.ListBlocks(*dir)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		for _, summary := range summaries {
+			if err := enc.Encode(summary); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "ULID\tMIN TIME\tMAX TIME\tDURATION\tSERIES\tCHUNKS\tSAMPLES\tSIZE\tLEVEL\tSOURCES\tPARENTS")
+	for _, summary := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\t%d\t%d\t%s\n",
+			summary.ULID,
+			formatMillis(summary.MinTime),
+			formatMillis(summary.MaxTime),
+			time.Duration(summary.MaxTime-summary.MinTime)*time.Millisecond,
+			summary.Series,
+			summary.Chunks,
+			summary.Samples,
+			humanReadableBytes(summary.Bytes),
+			summary.CompactionLevel,
+			summary.Sources,
+			strings.Join(summary.Parents, ","),
+		)
+	}
+	return w.Flush()
+}
+
+func formatMillis(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
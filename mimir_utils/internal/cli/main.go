@@ -15,6 +15,9 @@ Usage:
 
 Available subcommands:
   top-metrics    Analyze TSDB blocks and print the metrics using the most bytes.
+  analyze        Report label cardinality statistics for a set of TSDB blocks.
+  dump           Stream samples out of a set of TSDB blocks.
+  ls             List TSDB blocks with meta.json summaries.
 
 `)
 }
@@ -29,6 +32,12 @@ func Execute(args []string) error {
 	switch args[0] {
 	case "top-metrics":
 		return runTopMetrics(args[1:])
+	case "analyze":
+		return runAnalyze(args[1:])
+	case "dump":
+		return runDump(args[1:])
+	case "ls":
+		return runLs(args[1:])
 	case "help", "-h", "--help":
 		RootUsage()
 		return nil
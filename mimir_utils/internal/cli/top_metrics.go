@@ -14,6 +14,14 @@ func runTopMetrics(args []string) error {
 	fs := flag.NewFlagSet("top-metrics", flag.ContinueOnError)
 	dir := fs.String("dir", "", "Directory containing TSDB blocks")
 	limit := fs.Int("limit", 10, "Number of metrics to display (0 for all)")
+	includeHead := fs.Bool("include-head", false, "Open -dir via a read-only DB (persisted blocks only; the head block is not scanned)")
+	concurrency := fs.Int("concurrency", 0, "Number of blocks to scan concurrently (0 for GOMAXPROCS)")
+	minTimeStr := fs.String("min-time", "", "Only count chunks overlapping at or after this time (RFC3339 or unix ms)")
+	maxTimeStr := fs.String("max-time", "", "Only count chunks overlapping at or before this time (RFC3339 or unix ms)")
+	countSamples := fs.Bool("count-samples", false, "Decode chunks to also report samples per metric")
+
+	var matchers matcherFlags
+	fs.Var(&matchers, "match", "PromQL metric selector to restrict the scan to (repeatable)")
 
 	fs.Usage = func() {
 		fmt.Fprintf(fs.Output(), `Usage: mimir_utils top-metrics [options]
@@ -32,7 +40,33 @@ Options:
 		return fmt.Errorf("the -dir flag is required")
 	}
 
-	stats, err := analyzer.TopNMetrics(*dir, *limit)
+	minTime := int64(math.MinInt64)
+	if *minTimeStr != "" {
+		t, err := parseTime(*minTimeStr)
+		if err != nil {
+			return fmt.Errorf("parse -min-time: %w", err)
+		}
+		minTime = t
+	}
+
+	maxTime := int64(math.MaxInt64)
+	if *maxTimeStr != "" {
+		t, err := parseTime(*maxTimeStr)
+		if err != nil {
+			return fmt.Errorf("parse -max-time: %w", err)
+		}
+		maxTime = t
+	}
+
+	stats, err := analyzer.TopNMetrics(*dir, analyzer.TopMetricsOptions{
+		Limit:        *limit,
+		IncludeHead:  *includeHead,
+		Concurrency:  *concurrency,
+		MatcherSets:  matchers.sets,
+		MinTime:      minTime,
+		MaxTime:      maxTime,
+		CountSamples: *countSamples,
+	})
 	if err != nil {
 		return err
 	}
@@ -43,9 +77,16 @@ Options:
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
-	fmt.Fprintln(w, "METRIC\tBYTES\tSERIES\tCHUNKS")
-	for _, stat := range stats {
-		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", stat.Name, humanReadableBytes(stat.Bytes), stat.Series, stat.Chunks)
+	if *countSamples {
+		fmt.Fprintln(w, "METRIC\tBYTES\tSERIES\tCHUNKS\tSAMPLES")
+		for _, stat := range stats {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", stat.Name, humanReadableBytes(stat.Bytes), stat.Series, stat.Chunks, stat.Samples)
+		}
+	} else {
+		fmt.Fprintln(w, "METRIC\tBYTES\tSERIES\tCHUNKS")
+		for _, stat := range stats {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", stat.Name, humanReadableBytes(stat.Bytes), stat.Series, stat.Chunks)
+		}
 	}
 	return w.Flush()
 }